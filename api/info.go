@@ -0,0 +1,29 @@
+package api
+
+// Info is the JSON body returned by GET /info: every virtual service this
+// goloba instance is load balancing, and the destinations behind it.
+type Info struct {
+	Services []Service `json:"services"`
+}
+
+// Service is one virtual service (a listening address/port pair) and its
+// destinations.
+type Service struct {
+	Protocol     string        `json:"protocol"`
+	Address      string        `json:"address"`
+	Port         uint16        `json:"port"`
+	Schedule     string        `json:"schedule"`
+	Destinations []Destination `json:"destinations"`
+}
+
+// Destination is one real server behind a Service.
+type Destination struct {
+	Address      string `json:"address"`
+	Port         uint16 `json:"port"`
+	Forward      string `json:"forward"`
+	Weight       uint32 `json:"weight"`
+	ActiveConn   uint32 `json:"activeConn"`
+	InactiveConn uint32 `json:"inactiveConn"`
+	Detached     bool   `json:"detached"`
+	Locked       bool   `json:"locked"`
+}
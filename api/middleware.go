@@ -0,0 +1,43 @@
+package api
+
+import (
+	"crypto/subtle"
+	"net/http"
+)
+
+// AuthConfig is the server-side counterpart of golobactl's auth config: the
+// set of bearer tokens the API server accepts. An empty Tokens list means
+// authentication is not required (the historical, unauthenticated
+// behavior).
+type AuthConfig struct {
+	Tokens []string
+}
+
+// RequireBearerToken wraps next with a check that the request carries one
+// of conf.Tokens as an `Authorization: Bearer <token>` header, responding
+// 401 Unauthorized otherwise. If conf.Tokens is empty, requests are passed
+// through unchanged so existing deployments keep working until they opt in.
+func RequireBearerToken(conf AuthConfig, next http.Handler) http.Handler {
+	if len(conf.Tokens) == 0 {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tok := bearerToken(r)
+		for _, want := range conf.Tokens {
+			if tok != "" && subtle.ConstantTimeCompare([]byte(tok), []byte(want)) == 1 {
+				next.ServeHTTP(w, r)
+				return
+			}
+		}
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+	})
+}
+
+func bearerToken(r *http.Request) string {
+	const prefix = "Bearer "
+	h := r.Header.Get("Authorization")
+	if len(h) <= len(prefix) || h[:len(prefix)] != prefix {
+		return ""
+	}
+	return h[len(prefix):]
+}
@@ -0,0 +1,41 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRequireBearerToken(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	tests := []struct {
+		name       string
+		tokens     []string
+		authHeader string
+		wantStatus int
+	}{
+		{"no tokens configured passes through", nil, "", http.StatusOK},
+		{"missing header rejected", []string{"secret"}, "", http.StatusUnauthorized},
+		{"wrong token rejected", []string{"secret"}, "Bearer wrong", http.StatusUnauthorized},
+		{"correct token accepted", []string{"secret"}, "Bearer secret", http.StatusOK},
+		{"matches one of several tokens", []string{"a", "secret", "b"}, "Bearer secret", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := RequireBearerToken(AuthConfig{Tokens: tt.tokens}, ok)
+			req := httptest.NewRequest(http.MethodGet, "/info", nil)
+			if tt.authHeader != "" {
+				req.Header.Set("Authorization", tt.authHeader)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+		})
+	}
+}
@@ -0,0 +1,12 @@
+package api
+
+// Result is the JSON body returned by the attach, detach and unlock
+// endpoints of the goloba API server, and appears once per attempted op in a
+// TransactionResponse. Error is non-empty when this particular op failed;
+// for a non-atomic transaction, other ops may still have succeeded.
+type Result struct {
+	Service string `json:"service"`
+	Dest    string `json:"dest"`
+	Message string `json:"message"`
+	Error   string `json:"error,omitempty"`
+}
@@ -0,0 +1,196 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// Manager is the subset of the goloba load balancer core the v1 API needs:
+// reading the current state and applying a single attach/detach/unlock op.
+// The goloba daemon's load balancer implements this interface; Server only
+// depends on it so the HTTP plumbing below can be tested without a real
+// load balancer.
+type Manager interface {
+	Info() Info
+	Attach(svc, dest string, lock bool) error
+	Detach(svc, dest string, lock bool) error
+	Unlock(svc, dest string) error
+}
+
+// Server implements the goloba v1 HTTP API: GET /info and, under /v1/, the
+// per-destination attach/detach/unlock endpoints plus batch transactions.
+//
+// mu serializes every call into Manager: Manager implementations are not
+// assumed to be safe for concurrent attach/detach/unlock calls, and a
+// transaction's rollback bookkeeping (applied, below) would be corrupted by
+// an interleaved request from another connection.
+type Server struct {
+	Manager Manager
+	Auth    AuthConfig
+
+	mu sync.Mutex
+}
+
+// Routes returns the handler for Server, with bearer-token authentication
+// applied when s.Auth.Tokens is non-empty.
+func (s *Server) Routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/info", s.handleInfo)
+	mux.HandleFunc("/v1/services/", s.handleV1Destination)
+	mux.HandleFunc("/v1/transactions", s.handleV1Transaction)
+	return RequireBearerToken(s.Auth, mux)
+}
+
+func (s *Server) handleInfo(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	info := s.Manager.Info()
+	s.mu.Unlock()
+	writeJSON(w, http.StatusOK, info)
+}
+
+// handleV1Destination serves
+// POST /v1/services/{svc}/destinations/{dest}/{attach,detach,unlock}.
+func (s *Server) handleV1Destination(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	svc, dest, op, err := parseDestinationPath(r.URL.Path)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	var body AttachRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, fmt.Sprintf("failed to decode request body, err=%v", err), http.StatusBadRequest)
+			return
+		}
+	}
+
+	s.mu.Lock()
+	result := s.apply(Op{Op: op, Service: svc, Dest: dest, Lock: body.Lock})
+	s.mu.Unlock()
+	status := http.StatusOK
+	if result.Error != "" {
+		status = http.StatusConflict
+	}
+	writeJSON(w, status, result)
+}
+
+// parseDestinationPath extracts svc, dest and op from
+// /v1/services/{svc}/destinations/{dest}/{op}, where svc and dest are
+// URL-path-escaped since they contain a colon.
+func parseDestinationPath(path string) (svc, dest, op string, err error) {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	if len(parts) != 6 || parts[0] != "v1" || parts[1] != "services" || parts[3] != "destinations" {
+		return "", "", "", fmt.Errorf("malformed path %q", path)
+	}
+	svc, err = url.PathUnescape(parts[2])
+	if err != nil {
+		return "", "", "", fmt.Errorf("malformed service in path %q: %v", path, err)
+	}
+	dest, err = url.PathUnescape(parts[4])
+	if err != nil {
+		return "", "", "", fmt.Errorf("malformed dest in path %q: %v", path, err)
+	}
+	return svc, dest, parts[5], nil
+}
+
+// handleV1Transaction serves POST /v1/transactions: it applies every op in
+// order and, if Atomic is set, stops at the first failure and rolls back the
+// attach/detach ops applied so far (see rollback; unlock ops are not undone).
+func (s *Server) handleV1Transaction(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var req TransactionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, fmt.Sprintf("failed to decode request body, err=%v", err), http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	results := make([]Result, 0, len(req.Ops))
+	applied := make([]Op, 0, len(req.Ops))
+	failed := false
+	for _, op := range req.Ops {
+		result := s.apply(op)
+		results = append(results, result)
+		if result.Error != "" {
+			failed = true
+			if req.Atomic {
+				break
+			}
+			continue
+		}
+		applied = append(applied, op)
+	}
+
+	if failed && req.Atomic {
+		s.rollback(applied)
+		s.mu.Unlock()
+		writeJSON(w, http.StatusConflict, TransactionResponse{Results: results})
+		return
+	}
+	s.mu.Unlock()
+
+	status := http.StatusOK
+	if failed {
+		status = http.StatusMultiStatus
+	}
+	writeJSON(w, status, TransactionResponse{Results: results})
+}
+
+// apply invokes the manager operation named by op.Op, translating any error
+// into a Result with Error set rather than propagating it, so callers can
+// keep applying/rolling back the rest of a batch.
+func (s *Server) apply(op Op) Result {
+	var err error
+	switch op.Op {
+	case "attach":
+		err = s.Manager.Attach(op.Service, op.Dest, op.Lock)
+	case "detach":
+		err = s.Manager.Detach(op.Service, op.Dest, op.Lock)
+	case "unlock":
+		err = s.Manager.Unlock(op.Service, op.Dest)
+	default:
+		err = fmt.Errorf("unknown op %q", op.Op)
+	}
+	result := Result{Service: op.Service, Dest: op.Dest}
+	if err != nil {
+		result.Error = err.Error()
+	} else {
+		result.Message = "ok"
+	}
+	return result
+}
+
+// rollback undoes applied ops in reverse order: attach is undone with
+// detach and vice versa. unlock has no well-defined inverse, since it
+// doesn't record the lock state it replaced, so it is left as-is; this
+// matches the request's "first failure" semantics, which concern
+// attach/detach state rather than the unlock bookkeeping flag.
+func (s *Server) rollback(applied []Op) {
+	for i := len(applied) - 1; i >= 0; i-- {
+		op := applied[i]
+		switch op.Op {
+		case "attach":
+			s.Manager.Detach(op.Service, op.Dest, op.Lock)
+		case "detach":
+			s.Manager.Attach(op.Service, op.Dest, op.Lock)
+		}
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
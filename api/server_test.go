@@ -0,0 +1,160 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// fakeManager is an in-memory Manager used to test Server's routing and
+// transaction/rollback logic without a real goloba load balancer.
+type fakeManager struct {
+	attached map[string]bool // key: svc+"|"+dest
+	failOn   string          // "op:svc:dest" that should fail, or ""
+	calls    []string
+}
+
+func newFakeManager() *fakeManager {
+	return &fakeManager{attached: make(map[string]bool)}
+}
+
+func (m *fakeManager) key(svc, dest string) string { return svc + "|" + dest }
+
+func (m *fakeManager) maybeFail(op, svc, dest string) error {
+	if m.failOn == fmt.Sprintf("%s:%s:%s", op, svc, dest) {
+		return fmt.Errorf("simulated failure for %s", m.failOn)
+	}
+	return nil
+}
+
+func (m *fakeManager) Info() Info { return Info{} }
+
+func (m *fakeManager) Attach(svc, dest string, lock bool) error {
+	m.calls = append(m.calls, "attach:"+m.key(svc, dest))
+	if err := m.maybeFail("attach", svc, dest); err != nil {
+		return err
+	}
+	m.attached[m.key(svc, dest)] = true
+	return nil
+}
+
+func (m *fakeManager) Detach(svc, dest string, lock bool) error {
+	m.calls = append(m.calls, "detach:"+m.key(svc, dest))
+	if err := m.maybeFail("detach", svc, dest); err != nil {
+		return err
+	}
+	m.attached[m.key(svc, dest)] = false
+	return nil
+}
+
+func (m *fakeManager) Unlock(svc, dest string) error {
+	m.calls = append(m.calls, "unlock:"+m.key(svc, dest))
+	return m.maybeFail("unlock", svc, dest)
+}
+
+func postJSON(t *testing.T, h http.Handler, path string, body interface{}) *httptest.ResponseRecorder {
+	t.Helper()
+	data, err := json.Marshal(body)
+	if err != nil {
+		t.Fatalf("marshal request body: %v", err)
+	}
+	req := httptest.NewRequest(http.MethodPost, path, bytes.NewReader(data))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestServerHandleV1Destination(t *testing.T) {
+	m := newFakeManager()
+	srv := &Server{Manager: m}
+
+	rec := postJSON(t, srv.Routes(), "/v1/services/10.0.0.1:80/destinations/10.0.0.2:80/attach", AttachRequest{Lock: true})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", rec.Code, rec.Body.String())
+	}
+	if !m.attached[m.key("10.0.0.1:80", "10.0.0.2:80")] {
+		t.Error("expected destination to be attached")
+	}
+
+	var result Result
+	if err := json.Unmarshal(rec.Body.Bytes(), &result); err != nil {
+		t.Fatalf("unmarshal response: %v", err)
+	}
+	if result.Error != "" {
+		t.Errorf("unexpected error in result: %s", result.Error)
+	}
+}
+
+func TestServerHandleV1Transaction_NonAtomicContinuesPastFailure(t *testing.T) {
+	m := newFakeManager()
+	m.failOn = "detach:svc1:dest1"
+	srv := &Server{Manager: m}
+
+	req := TransactionRequest{
+		Atomic: false,
+		Ops: []Op{
+			{Op: "attach", Service: "svc1", Dest: "dest2"},
+			{Op: "detach", Service: "svc1", Dest: "dest1"},
+			{Op: "attach", Service: "svc1", Dest: "dest3"},
+		},
+	}
+	rec := postJSON(t, srv.Routes(), "/v1/transactions", req)
+	if rec.Code != http.StatusMultiStatus {
+		t.Fatalf("status = %d, want %d, body = %s", rec.Code, http.StatusMultiStatus, rec.Body.String())
+	}
+
+	var resp TransactionResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("unmarshal response: %v", err)
+	}
+	if len(resp.Results) != 3 {
+		t.Fatalf("got %d results, want 3", len(resp.Results))
+	}
+	if resp.Results[1].Error == "" {
+		t.Error("expected op 1 (the simulated failure) to report an error")
+	}
+	if !m.attached[m.key("svc1", "dest2")] || !m.attached[m.key("svc1", "dest3")] {
+		t.Error("expected the other ops to still have been applied")
+	}
+}
+
+func TestServerHandleV1Transaction_AtomicRollsBackOnFailure(t *testing.T) {
+	m := newFakeManager()
+	m.failOn = "attach:svc1:dest2"
+	srv := &Server{Manager: m}
+
+	req := TransactionRequest{
+		Atomic: true,
+		Ops: []Op{
+			{Op: "attach", Service: "svc1", Dest: "dest1"},
+			{Op: "attach", Service: "svc1", Dest: "dest2"}, // fails
+			{Op: "attach", Service: "svc1", Dest: "dest3"}, // never applied
+		},
+	}
+	rec := postJSON(t, srv.Routes(), "/v1/transactions", req)
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("status = %d, want %d, body = %s", rec.Code, http.StatusConflict, rec.Body.String())
+	}
+
+	if m.attached[m.key("svc1", "dest1")] {
+		t.Error("expected the first op to have been rolled back (detached) after the atomic failure")
+	}
+	if m.attached[m.key("svc1", "dest3")] {
+		t.Error("expected the op after the failure to never have been applied")
+	}
+}
+
+func TestServerRequiresBearerTokenWhenConfigured(t *testing.T) {
+	m := newFakeManager()
+	srv := &Server{Manager: m, Auth: AuthConfig{Tokens: []string{"secret"}}}
+
+	req := httptest.NewRequest(http.MethodGet, "/info", nil)
+	rec := httptest.NewRecorder()
+	srv.Routes().ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusUnauthorized)
+	}
+}
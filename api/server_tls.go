@@ -0,0 +1,35 @@
+package api
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+)
+
+// ServerTLSConfig builds the tls.Config the goloba API server's listener
+// should use. If caFile is set, client certificates are required and
+// verified against it (mTLS); otherwise TLS is configured without client
+// authentication, preserving the unauthenticated behavior existing
+// deployments rely on until they opt in.
+func ServerTLSConfig(certFile, keyFile, caFile string) (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load server certificate, certFile=%s, keyFile=%s, err=%v", certFile, keyFile, err)
+	}
+	conf := &tls.Config{Certificates: []tls.Certificate{cert}}
+
+	if caFile != "" {
+		pem, err := ioutil.ReadFile(caFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read client CA file, file=%s, err=%v", caFile, err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("failed to parse client CA file, file=%s", caFile)
+		}
+		conf.ClientCAs = pool
+		conf.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return conf, nil
+}
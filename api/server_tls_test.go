@@ -0,0 +1,114 @@
+package api
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeTestCertPair generates a minimal self-signed certificate/key pair and
+// writes them as PEM files under t.TempDir(), returning their paths.
+func writeTestCertPair(t *testing.T) (certFile, keyFile string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "goloba-test"},
+		NotBefore:    time.Unix(0, 0),
+		NotAfter:     time.Unix(0, 0).Add(24 * time.Hour),
+		KeyUsage:     x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("CreateCertificate: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("MarshalECPrivateKey: %v", err)
+	}
+
+	dir := t.TempDir()
+	certFile = filepath.Join(dir, "cert.pem")
+	keyFile = filepath.Join(dir, "key.pem")
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600); err != nil {
+		t.Fatalf("write cert: %v", err)
+	}
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600); err != nil {
+		t.Fatalf("write key: %v", err)
+	}
+	return certFile, keyFile
+}
+
+func TestServerTLSConfigWithoutCARequiresNoClientCert(t *testing.T) {
+	certFile, keyFile := writeTestCertPair(t)
+
+	conf, err := ServerTLSConfig(certFile, keyFile, "")
+	if err != nil {
+		t.Fatalf("ServerTLSConfig: %v", err)
+	}
+	if conf.ClientAuth != tls.NoClientCert {
+		t.Errorf("ClientAuth = %v, want NoClientCert when no CA file is configured", conf.ClientAuth)
+	}
+	if conf.ClientCAs != nil {
+		t.Error("expected ClientCAs to be nil when no CA file is configured")
+	}
+	if len(conf.Certificates) != 1 {
+		t.Errorf("got %d certificates, want 1", len(conf.Certificates))
+	}
+}
+
+func TestServerTLSConfigWithCARequiresClientCert(t *testing.T) {
+	certFile, keyFile := writeTestCertPair(t)
+	caFile, _ := writeTestCertPair(t)
+
+	conf, err := ServerTLSConfig(certFile, keyFile, caFile)
+	if err != nil {
+		t.Fatalf("ServerTLSConfig: %v", err)
+	}
+	if conf.ClientAuth != tls.RequireAndVerifyClientCert {
+		t.Errorf("ClientAuth = %v, want RequireAndVerifyClientCert when a CA file is configured", conf.ClientAuth)
+	}
+	if conf.ClientCAs == nil {
+		t.Error("expected ClientCAs to be set when a CA file is configured")
+	}
+}
+
+func TestServerTLSConfigMissingCert(t *testing.T) {
+	dir := t.TempDir()
+	_, err := ServerTLSConfig(filepath.Join(dir, "does-not-exist-cert.pem"), filepath.Join(dir, "does-not-exist-key.pem"), "")
+	if err == nil {
+		t.Error("expected an error for a missing server certificate")
+	}
+}
+
+func TestServerTLSConfigMissingCAFile(t *testing.T) {
+	certFile, keyFile := writeTestCertPair(t)
+	_, err := ServerTLSConfig(certFile, keyFile, filepath.Join(t.TempDir(), "does-not-exist-ca.pem"))
+	if err == nil {
+		t.Error("expected an error for a missing client CA file")
+	}
+}
+
+func TestServerTLSConfigInvalidCAFile(t *testing.T) {
+	certFile, keyFile := writeTestCertPair(t)
+	badCA := filepath.Join(t.TempDir(), "bad-ca.pem")
+	if err := os.WriteFile(badCA, []byte("not a pem file"), 0600); err != nil {
+		t.Fatalf("write bad CA file: %v", err)
+	}
+	if _, err := ServerTLSConfig(certFile, keyFile, badCA); err == nil {
+		t.Error("expected an error for an unparseable client CA file")
+	}
+}
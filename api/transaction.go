@@ -0,0 +1,30 @@
+package api
+
+// Op is a single operation inside a POST /v1/transactions request body.
+type Op struct {
+	Op      string `json:"op"` // "attach", "detach" or "unlock"
+	Service string `json:"service"`
+	Dest    string `json:"dest"`
+	Lock    bool   `json:"lock,omitempty"`
+}
+
+// TransactionRequest is the body of POST /v1/transactions. If Atomic is
+// true, the server stops at the first failure and rolls back the attach and
+// detach ops applied so far; unlock ops are not undone.
+type TransactionRequest struct {
+	Ops    []Op `json:"ops"`
+	Atomic bool `json:"atomic"`
+}
+
+// TransactionResponse is the body returned by POST /v1/transactions: one
+// Result per attempted Op, in request order. An atomic transaction stops at
+// its first failure, so the ops after it have no Result.
+type TransactionResponse struct {
+	Results []Result `json:"results"`
+}
+
+// AttachRequest is the body of POST /v1/services/{svc}/destinations/{dest}/attach
+// and the symmetric detach/unlock endpoints.
+type AttachRequest struct {
+	Lock bool `json:"lock"`
+}
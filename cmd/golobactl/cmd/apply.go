@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/spf13/cobra"
+	yaml "gopkg.in/yaml.v2"
+
+	"github.com/masa23/goloba/api"
+	"github.com/masa23/goloba/internal/apiclient"
+	"github.com/masa23/goloba/internal/fanout"
+)
+
+var applyFile string
+
+// opsFile is the YAML shape of an `apply -f` batch file, e.g.:
+//
+//	atomic: true
+//	ops:
+//	  - op: detach
+//	    service: 192.168.122.2:80
+//	    dest: 192.168.122.62:80
+//	    lock: true
+type opsFile struct {
+	Ops    []opsFileOp `yaml:"ops"`
+	Atomic bool        `yaml:"atomic"`
+}
+
+type opsFileOp struct {
+	Op      string `yaml:"op"`
+	Service string `yaml:"service"`
+	Dest    string `yaml:"dest"`
+	Lock    bool   `yaml:"lock"`
+}
+
+var applyCmd = &cobra.Command{
+	Use:   "apply",
+	Short: "Submit a batch of attach/detach/unlock ops from a file as one transaction",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runApply()
+	},
+}
+
+func init() {
+	applyCmd.Flags().StringVarP(&applyFile, "file", "f", "", "ops file in YAML (required)")
+	applyCmd.MarkFlagRequired("file")
+	rootCmd.AddCommand(applyCmd)
+}
+
+func runApply() error {
+	req, err := loadOpsFile(applyFile)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	results := fanout.Run(ctx, a.targets(func(c *apiclient.Client) func(context.Context) (interface{}, error) {
+		return func(ctx context.Context) (interface{}, error) {
+			return c.Transaction(ctx, req)
+		}
+	}))
+
+	var hadError bool
+	for _, r := range results {
+		log := a.logger.Named("apply").With("serverURL", r.Label)
+		if r.Err != nil {
+			log.Error("failed to apply transaction", "err", r.Err)
+			hadError = true
+			continue
+		}
+		resp := r.Value.(*api.TransactionResponse)
+		fmt.Printf("%s:\n", r.Label)
+		for i, res := range resp.Results {
+			if i >= len(req.Ops) {
+				log.Error("server returned more results than ops were submitted", "resultCount", len(resp.Results), "opCount", len(req.Ops))
+				hadError = true
+				break
+			}
+			if res.Error != "" {
+				fmt.Printf("  %s %s -> %s: error: %s\n", req.Ops[i].Op, req.Ops[i].Service, req.Ops[i].Dest, res.Error)
+				hadError = true
+				continue
+			}
+			fmt.Printf("  %s %s -> %s: %s\n", req.Ops[i].Op, req.Ops[i].Service, req.Ops[i].Dest, res.Message)
+		}
+	}
+	if hadError {
+		return fmt.Errorf("one or more API servers returned an error")
+	}
+	return nil
+}
+
+func loadOpsFile(file string) (api.TransactionRequest, error) {
+	buf, err := ioutil.ReadFile(file)
+	if err != nil {
+		return api.TransactionRequest{}, fmt.Errorf("failed to read ops file, file=%s, err=%v", file, err)
+	}
+	var f opsFile
+	if err := yaml.Unmarshal(buf, &f); err != nil {
+		return api.TransactionRequest{}, fmt.Errorf("failed to parse ops file, file=%s, err=%v", file, err)
+	}
+
+	req := api.TransactionRequest{Atomic: f.Atomic, Ops: make([]api.Op, len(f.Ops))}
+	for i, op := range f.Ops {
+		req.Ops[i] = api.Op{Op: op.Op, Service: op.Service, Dest: op.Dest, Lock: op.Lock}
+	}
+	return req, nil
+}
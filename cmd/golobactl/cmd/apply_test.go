@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadOpsFile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "ops.yml")
+	const content = `
+atomic: true
+ops:
+  - op: detach
+    service: 192.168.122.2:80
+    dest: 192.168.122.62:80
+    lock: true
+  - op: unlock
+    service: 192.168.122.2:80
+    dest: 192.168.122.240:80
+`
+	if err := os.WriteFile(file, []byte(content), 0600); err != nil {
+		t.Fatalf("write ops file: %v", err)
+	}
+
+	req, err := loadOpsFile(file)
+	if err != nil {
+		t.Fatalf("loadOpsFile: %v", err)
+	}
+	if !req.Atomic {
+		t.Error("expected Atomic to be true")
+	}
+	if len(req.Ops) != 2 {
+		t.Fatalf("got %d ops, want 2", len(req.Ops))
+	}
+	if req.Ops[0].Op != "detach" || req.Ops[0].Service != "192.168.122.2:80" || req.Ops[0].Dest != "192.168.122.62:80" || !req.Ops[0].Lock {
+		t.Errorf("op 0 = %+v, want detach 192.168.122.2:80 -> 192.168.122.62:80 lock=true", req.Ops[0])
+	}
+	if req.Ops[1].Op != "unlock" || req.Ops[1].Lock {
+		t.Errorf("op 1 = %+v, want unlock with lock=false", req.Ops[1])
+	}
+}
+
+func TestLoadOpsFileMissing(t *testing.T) {
+	_, err := loadOpsFile(filepath.Join(t.TempDir(), "does-not-exist.yml"))
+	if err == nil {
+		t.Error("expected an error for a missing ops file")
+	}
+}
+
+func TestLoadOpsFileInvalidYAML(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "ops.yml")
+	if err := os.WriteFile(file, []byte("not: [valid"), 0600); err != nil {
+		t.Fatalf("write ops file: %v", err)
+	}
+	if _, err := loadOpsFile(file); err == nil {
+		t.Error("expected an error for invalid YAML")
+	}
+}
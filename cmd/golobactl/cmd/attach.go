@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/masa23/goloba/internal/apiclient"
+	"github.com/masa23/goloba/internal/fanout"
+)
+
+var (
+	attachServiceAddr string
+	attachDestAddr    string
+	attachLock        bool
+)
+
+var attachCmd = &cobra.Command{
+	Use:   "attach",
+	Short: "Manually attach a destination",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runMutation("attach", func(c *apiclient.Client) func(context.Context) (interface{}, error) {
+			return func(ctx context.Context) (interface{}, error) {
+				return c.Attach(ctx, attachServiceAddr, attachDestAddr, attachLock)
+			}
+		})
+	},
+}
+
+func init() {
+	attachCmd.Flags().StringVarP(&attachServiceAddr, "service", "s", "", "service address in <IPAddress>:<port> form")
+	attachCmd.Flags().StringVarP(&attachDestAddr, "dest", "d", "", "destination address in <IPAddress>:<port> form")
+	attachCmd.Flags().BoolVar(&attachLock, "lock", true, "lock attach regardless of future healthcheck results")
+	rootCmd.AddCommand(attachCmd)
+}
+
+// runMutation fans a single-destination mutation (attach/detach/unlock) out
+// to every configured API server and prints each server's result.
+func runMutation(op string, call func(c *apiclient.Client) func(context.Context) (interface{}, error)) error {
+	ctx := context.Background()
+	results := fanout.Run(ctx, a.targets(call))
+
+	var hadError bool
+	for _, r := range results {
+		log := a.logger.Named(op).With("serverURL", r.Label)
+		if r.Err != nil {
+			log.Error("failed to "+op, "err", r.Err)
+			hadError = true
+			continue
+		}
+		fmt.Printf("%s:\n%+v\n", r.Label, r.Value)
+	}
+	if hadError {
+		return fmt.Errorf("one or more API servers returned an error")
+	}
+	return nil
+}
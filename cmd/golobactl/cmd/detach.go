@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+
+	"github.com/masa23/goloba/internal/apiclient"
+)
+
+var (
+	detachServiceAddr string
+	detachDestAddr    string
+	detachLock        bool
+)
+
+var detachCmd = &cobra.Command{
+	Use:   "detach",
+	Short: "Manually detach a destination",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runMutation("detach", func(c *apiclient.Client) func(context.Context) (interface{}, error) {
+			return func(ctx context.Context) (interface{}, error) {
+				return c.Detach(ctx, detachServiceAddr, detachDestAddr, detachLock)
+			}
+		})
+	},
+}
+
+func init() {
+	detachCmd.Flags().StringVarP(&detachServiceAddr, "service", "s", "", "service address in <IPAddress>:<port> form")
+	detachCmd.Flags().StringVarP(&detachDestAddr, "dest", "d", "", "destination address in <IPAddress>:<port> form")
+	detachCmd.Flags().BoolVar(&detachLock, "lock", true, "lock detach regardless of future healthcheck results")
+	rootCmd.AddCommand(detachCmd)
+}
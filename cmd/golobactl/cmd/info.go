@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/masa23/goloba/api"
+	"github.com/masa23/goloba/internal/apiclient"
+	"github.com/masa23/goloba/internal/fanout"
+)
+
+var (
+	infoFormat   string
+	infoWatch    bool
+	infoInterval time.Duration
+)
+
+var infoCmd = &cobra.Command{
+	Use:   "info",
+	Short: "Show the current state of every configured goloba API server",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if infoWatch {
+			return runInfoWatch()
+		}
+		return runInfo()
+	},
+}
+
+func init() {
+	infoCmd.Flags().StringVar(&infoFormat, "format", "text", "result format, 'text', 'json', 'prometheus' or 'json-stream'")
+	infoCmd.Flags().BoolVarP(&infoWatch, "watch", "w", false, "repeatedly poll every API server and redraw the table, like ipvsadm -Lnc --rate")
+	infoCmd.Flags().DurationVar(&infoInterval, "interval", 2*time.Second, "poll interval in --watch mode")
+	rootCmd.AddCommand(infoCmd)
+}
+
+// fetchInfo fans Info() out to every configured API server.
+func fetchInfo(ctx context.Context) []fanout.Result {
+	return fanout.Run(ctx, a.targets(func(c *apiclient.Client) func(context.Context) (interface{}, error) {
+		return func(ctx context.Context) (interface{}, error) {
+			return c.Info(ctx)
+		}
+	}))
+}
+
+func runInfo() error {
+	results := fetchInfo(context.Background())
+
+	var hadError bool
+	for _, r := range results {
+		log := a.logger.Named("info").With("serverURL", r.Label)
+		if r.Err != nil {
+			log.Error("failed to fetch info", "err", r.Err)
+			hadError = true
+			continue
+		}
+		info := r.Value.(*api.Info)
+		switch infoFormat {
+		case "json":
+			data, err := json.Marshal(info)
+			if err != nil {
+				log.Error("failed to marshal info", "err", err)
+				hadError = true
+				continue
+			}
+			fmt.Printf("%s:\n%s\n", r.Label, string(data))
+		case "prometheus":
+			writePrometheus(r.Label, info)
+		case "json-stream":
+			writeJSONStreamRecord(r.Label, info)
+		default:
+			printInfoText(r.Label, info, nil)
+		}
+	}
+	if hadError {
+		return fmt.Errorf("one or more API servers returned an error")
+	}
+	return nil
+}
+
+// printInfoText renders info in the ipvsadm-like layout golobactl has
+// always used:
+//
+// [root@lbvm01 ~]# golobactl info
+// http://localhost:8880:
+// Prot LocalAddress:Port Scheduler Flags
+//   -> RemoteAddress:Port           Forward Weight ActiveConn InActConn Detached Locked
+// tcp  192.168.122.2:80 wrr
+//   -> 192.168.122.62:80            droute  100    0          0         true     false
+//   -> 192.168.122.240:80           droute  500    0          0         false    false
+//
+// When prev is non-nil (in --watch mode), ActiveConn/InActConn are
+// highlighted green when they grew since the previous poll and red when
+// they shrank, the same way `ipvsadm -Lnc --rate` highlights rates.
+func printInfoText(server string, info *api.Info, prev map[destKey]api.Destination) {
+	fmt.Printf("%s:\n", server)
+	fmt.Printf("Prot LocalAddress:Port Scheduler Flags\n")
+	fmt.Printf("  -> RemoteAddress:Port           Forward Weight ActiveConn InActConn Detached Locked\n")
+	for _, sr := range info.Services {
+		fmt.Printf("%-4s %s:%d %s\n", sr.Protocol, sr.Address, sr.Port, sr.Schedule)
+		for _, d := range sr.Destinations {
+			hostPort := net.JoinHostPort(d.Address, strconv.Itoa(int(d.Port)))
+			activeConn := formatConnCount(d.ActiveConn, 10, server, sr, d, prev, func(pd api.Destination) uint32 { return pd.ActiveConn })
+			inactiveConn := formatConnCount(d.InactiveConn, 9, server, sr, d, prev, func(pd api.Destination) uint32 { return pd.InactiveConn })
+			fmt.Printf("  -> %-28s %-7s %-6d %s %s %-8v %v\n", hostPort, d.Forward, d.Weight, activeConn, inactiveConn, d.Detached, d.Locked)
+		}
+	}
+	fmt.Println()
+}
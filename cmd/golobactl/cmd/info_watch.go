@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/masa23/goloba/api"
+)
+
+const (
+	ansiClearScreen = "\033[H\033[2J"
+	ansiGreen       = "\033[32m"
+	ansiRed         = "\033[31m"
+	ansiReset       = "\033[0m"
+)
+
+// destKey identifies one destination across polls, so ActiveConn/InActConn
+// deltas survive service/destination reordering between ticks.
+type destKey struct {
+	server, service, dest string
+}
+
+func destKeyFor(server string, sr api.Service, d api.Destination) destKey {
+	return destKey{
+		server:  server,
+		service: fmt.Sprintf("%s:%d", sr.Address, sr.Port),
+		dest:    fmt.Sprintf("%s:%d", d.Address, d.Port),
+	}
+}
+
+// formatConnCount renders count left-justified to width, wrapped in green
+// if it grew since the last poll, red if it shrank, or plain if prev is nil
+// (not in --watch mode) or unchanged.
+func formatConnCount(count uint32, width int, server string, sr api.Service, d api.Destination, prev map[destKey]api.Destination, field func(api.Destination) uint32) string {
+	padded := fmt.Sprintf("%-*d", width, count)
+	if prev == nil {
+		return padded
+	}
+	pd, ok := prev[destKeyFor(server, sr, d)]
+	if !ok {
+		return padded
+	}
+	switch {
+	case count > field(pd):
+		return ansiGreen + padded + ansiReset
+	case count < field(pd):
+		return ansiRed + padded + ansiReset
+	default:
+		return padded
+	}
+}
+
+func snapshotDests(server string, info *api.Info, into map[destKey]api.Destination) {
+	for _, sr := range info.Services {
+		for _, d := range sr.Destinations {
+			into[destKeyFor(server, sr, d)] = d
+		}
+	}
+}
+
+// runInfoWatch repeatedly polls every API server every --interval, clears
+// the terminal and redraws the table, highlighting ActiveConn/InActConn
+// deltas, similar to `ipvsadm -Lnc --rate`. It exits cleanly on SIGINT.
+func runInfoWatch() error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	prev := make(map[destKey]api.Destination)
+	ticker := time.NewTicker(infoInterval)
+	defer ticker.Stop()
+
+	for {
+		results := fetchInfo(ctx)
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		if infoFormat == "text" {
+			fmt.Print(ansiClearScreen)
+		}
+		next := make(map[destKey]api.Destination)
+		for _, r := range results {
+			log := a.logger.Named("info").With("serverURL", r.Label)
+			if r.Err != nil {
+				log.Error("failed to fetch info", "err", r.Err)
+				continue
+			}
+			info := r.Value.(*api.Info)
+			switch infoFormat {
+			case "prometheus":
+				writePrometheus(r.Label, info)
+			case "json-stream":
+				writeJSONStreamRecord(r.Label, info)
+			default:
+				printInfoText(r.Label, info, prev)
+			}
+			snapshotDests(r.Label, info, next)
+		}
+		prev = next
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// writePrometheus emits info in the Prometheus text exposition format so a
+// sidecar can scrape `golobactl info --watch --format prometheus`.
+func writePrometheus(server string, info *api.Info) {
+	for _, sr := range info.Services {
+		service := fmt.Sprintf("%s:%d", sr.Address, sr.Port)
+		for _, d := range sr.Destinations {
+			dest := fmt.Sprintf("%s:%d", d.Address, d.Port)
+			labels := fmt.Sprintf(`server=%q,service=%q,dest=%q`, server, service, dest)
+			fmt.Printf("goloba_active_conn{%s} %d\n", labels, d.ActiveConn)
+			fmt.Printf("goloba_inactive_conn{%s} %d\n", labels, d.InactiveConn)
+			fmt.Printf("goloba_weight{%s} %d\n", labels, d.Weight)
+			fmt.Printf("goloba_detached{%s} %d\n", labels, boolToInt(d.Detached))
+		}
+	}
+}
+
+// jsonStreamRecord is one line of `--format json-stream` output: a single
+// poll's info for one server, so downstream tools (jq, fluent-bit) can
+// process it line by line without buffering a whole snapshot.
+type jsonStreamRecord struct {
+	Server string   `json:"server"`
+	Info   api.Info `json:"info"`
+}
+
+func writeJSONStreamRecord(server string, info *api.Info) {
+	data, err := json.Marshal(jsonStreamRecord{Server: server, Info: *info})
+	if err != nil {
+		a.logger.Named("info").With("serverURL", server).Error("failed to marshal json-stream record", "err", err)
+		return
+	}
+	fmt.Println(string(data))
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
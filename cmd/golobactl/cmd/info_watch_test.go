@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/masa23/goloba/api"
+)
+
+func TestDestKeyForIdentifiesDestinationAcrossPolls(t *testing.T) {
+	sr := api.Service{Address: "10.0.0.1", Port: 80}
+	d := api.Destination{Address: "10.0.0.2", Port: 80}
+
+	k1 := destKeyFor("server-a", sr, d)
+	k2 := destKeyFor("server-a", sr, d)
+	if k1 != k2 {
+		t.Errorf("expected identical keys for the same server/service/dest, got %+v and %+v", k1, k2)
+	}
+
+	if k3 := destKeyFor("server-b", sr, d); k3 == k1 {
+		t.Error("expected a different key for a different server")
+	}
+}
+
+func TestFormatConnCountColoring(t *testing.T) {
+	server := "server-a"
+	sr := api.Service{Address: "10.0.0.1", Port: 80}
+	d := api.Destination{Address: "10.0.0.2", Port: 80}
+	field := func(pd api.Destination) uint32 { return pd.ActiveConn }
+
+	t.Run("no previous snapshot is uncolored", func(t *testing.T) {
+		got := formatConnCount(5, 10, server, sr, d, nil, field)
+		if strings.Contains(got, ansiGreen) || strings.Contains(got, ansiRed) {
+			t.Errorf("expected no color escape with prev == nil, got %q", got)
+		}
+	})
+
+	t.Run("unseen destination is uncolored", func(t *testing.T) {
+		prev := map[destKey]api.Destination{}
+		got := formatConnCount(5, 10, server, sr, d, prev, field)
+		if strings.Contains(got, ansiGreen) || strings.Contains(got, ansiRed) {
+			t.Errorf("expected no color escape for a destination missing from prev, got %q", got)
+		}
+	})
+
+	t.Run("increase is green", func(t *testing.T) {
+		prev := map[destKey]api.Destination{destKeyFor(server, sr, d): {ActiveConn: 3}}
+		got := formatConnCount(5, 10, server, sr, d, prev, field)
+		if !strings.Contains(got, ansiGreen) {
+			t.Errorf("expected green for an increase, got %q", got)
+		}
+	})
+
+	t.Run("decrease is red", func(t *testing.T) {
+		prev := map[destKey]api.Destination{destKeyFor(server, sr, d): {ActiveConn: 8}}
+		got := formatConnCount(5, 10, server, sr, d, prev, field)
+		if !strings.Contains(got, ansiRed) {
+			t.Errorf("expected red for a decrease, got %q", got)
+		}
+	})
+
+	t.Run("unchanged is uncolored", func(t *testing.T) {
+		prev := map[destKey]api.Destination{destKeyFor(server, sr, d): {ActiveConn: 5}}
+		got := formatConnCount(5, 10, server, sr, d, prev, field)
+		if strings.Contains(got, ansiGreen) || strings.Contains(got, ansiRed) {
+			t.Errorf("expected no color escape for an unchanged count, got %q", got)
+		}
+	})
+}
+
+func TestBoolToInt(t *testing.T) {
+	if boolToInt(true) != 1 {
+		t.Error("boolToInt(true) should be 1")
+	}
+	if boolToInt(false) != 0 {
+		t.Error("boolToInt(false) should be 0")
+	}
+}
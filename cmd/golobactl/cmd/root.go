@@ -0,0 +1,215 @@
+// Package cmd implements the golobactl subcommand tree.
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	hclog "github.com/hashicorp/go-hclog"
+	"github.com/spf13/cobra"
+	yaml "gopkg.in/yaml.v2"
+
+	"github.com/masa23/goloba/internal/apiclient"
+	"github.com/masa23/goloba/internal/fanout"
+)
+
+type cliConfig struct {
+	Timeout    time.Duration     `yaml:"timeout"`
+	APIServers []apiServerConfig `yaml:"api_servers"`
+	Log        logConfig         `yaml:"log"`
+}
+
+type apiServerConfig struct {
+	URL  string               `yaml:"url"`
+	TLS  apiclient.TLSConfig  `yaml:"tls"`
+	Auth apiclient.AuthConfig `yaml:"auth"`
+}
+
+type logConfig struct {
+	Level  string `yaml:"level"`
+	Format string `yaml:"format"`
+	File   string `yaml:"file"`
+}
+
+// app holds the state shared by every subcommand: the parsed config, the
+// logger and one apiclient.Client per configured API server.
+type app struct {
+	config  *cliConfig
+	logger  hclog.Logger
+	logFile *os.File
+	clients []*apiclient.Client
+}
+
+var (
+	a app
+
+	configFile  string
+	timeout     time.Duration
+	logLevel    string
+	logFormat   string
+	logFilePath string
+	serverURL   string
+	apiVersion  string
+)
+
+// rootCmd is the golobactl entry point. Each subcommand is registered on it
+// in its own file (info.go, attach.go, detach.go, unlock.go, ...), so adding
+// a new one only means adding a new file and calling rootCmd.AddCommand in
+// its init.
+var rootCmd = &cobra.Command{
+	Use:           "golobactl",
+	Short:         "Control and inspect goloba load balancers",
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		return a.init()
+	},
+	PersistentPostRun: func(cmd *cobra.Command, args []string) {
+		a.close()
+	},
+}
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&configFile, "config", "/etc/goloba/golobactl.yml", "config file")
+	rootCmd.PersistentFlags().DurationVar(&timeout, "timeout", 0, "per-request timeout (overrides the config file)")
+	rootCmd.PersistentFlags().StringVar(&logLevel, "log-level", "", "log level, one of TRACE, DEBUG, INFO, WARN, ERROR")
+	rootCmd.PersistentFlags().StringVar(&logFormat, "log-format", "", "log format, 'json' or 'logfmt' (any value but 'json' gives hclog's standard key=value text lines, not strict logfmt)")
+	rootCmd.PersistentFlags().StringVar(&logFilePath, "log-file", "", "write log output to this file instead of stderr")
+	rootCmd.PersistentFlags().StringVar(&serverURL, "server", "", "target only this API server URL instead of every server in the config")
+	rootCmd.PersistentFlags().StringVar(&apiVersion, "api-version", "v1", "API protocol to speak to the goloba API server, 'v1' or 'v0' (legacy GET-with-query-string endpoints)")
+}
+
+// Execute runs the golobactl command tree. It is the only exported symbol
+// cmd/golobactl/main.go needs to call.
+func Execute() {
+	if err := rootCmd.Execute(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func (a *app) init() error {
+	conf, err := loadConfig(configFile)
+	if err != nil {
+		return err
+	}
+	if timeout != 0 {
+		conf.Timeout = timeout
+	}
+	if logLevel != "" {
+		conf.Log.Level = logLevel
+	}
+	if logFormat != "" {
+		conf.Log.Format = logFormat
+	}
+	if logFilePath != "" {
+		conf.Log.File = logFilePath
+	}
+	if serverURL != "" {
+		conf.APIServers = filterAPIServers(conf.APIServers, serverURL)
+	}
+
+	out := os.Stderr
+	if conf.Log.File != "" {
+		f, err := os.OpenFile(conf.Log.File, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			return fmt.Errorf("failed to open log file, file=%s, err=%v", conf.Log.File, err)
+		}
+		out = f
+		a.logFile = f
+	}
+	level := hclog.LevelFromString(conf.Log.Level)
+	if level == hclog.NoLevel {
+		level = hclog.Info
+	}
+
+	a.config = conf
+	a.logger = hclog.New(&hclog.LoggerOptions{
+		Name:       "golobactl",
+		Level:      level,
+		Output:     out,
+		JSONFormat: conf.Log.Format == "json",
+	})
+
+	a.clients = make([]*apiclient.Client, len(conf.APIServers))
+	for i, s := range conf.APIServers {
+		c, err := apiclient.New(apiclient.Config{URL: s.URL, TLS: s.TLS, Auth: s.Auth, APIVersion: apiVersion}, conf.Timeout)
+		if err != nil {
+			return fmt.Errorf("failed to build API client, serverURL=%s, err=%v", s.URL, err)
+		}
+		a.clients[i] = c
+	}
+	a.watchForSIGHUP()
+	return nil
+}
+
+// watchForSIGHUP refreshes every client's certificate/key/CA files on
+// SIGHUP (bearer tokens are read on every request anyway). This is on top of each apiclient.Client's own automatic
+// mtime-based reload (see reloadIfCredentialsRotated), which already covers
+// a long-running `info --watch`; SIGHUP exists for callers that want an
+// immediate, explicit "reload now" signal instead of waiting for the next
+// poll tick.
+func (a *app) watchForSIGHUP() {
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, syscall.SIGHUP)
+	go func() {
+		for range ch {
+			for i, c := range a.clients {
+				if err := c.Refresh(); err != nil {
+					a.logger.Error("failed to refresh API client credentials", "serverURL", a.config.APIServers[i].URL, "err", err)
+				}
+			}
+		}
+	}()
+}
+
+// targets builds one fanout.Target per configured API server, each labeled
+// with that server's URL.
+func (a *app) targets(call func(c *apiclient.Client) func(ctx context.Context) (interface{}, error)) []fanout.Target {
+	targets := make([]fanout.Target, len(a.clients))
+	for i, c := range a.clients {
+		targets[i] = fanout.Target{Label: a.config.APIServers[i].URL, Call: call(c)}
+	}
+	return targets
+}
+
+func (a *app) close() {
+	if a.logFile != nil {
+		a.logFile.Close()
+	}
+}
+
+// filterAPIServers restricts servers to the one(s) matching url, so
+// `--server` targets an already-configured server (keeping its TLS/Auth
+// settings) rather than a bare, credential-less stand-in. If url isn't
+// configured, it falls through to a bare entry so it can still be used
+// against a server with no auth requirements.
+func filterAPIServers(servers []apiServerConfig, url string) []apiServerConfig {
+	var matched []apiServerConfig
+	for _, s := range servers {
+		if s.URL == url {
+			matched = append(matched, s)
+		}
+	}
+	if len(matched) == 0 {
+		return []apiServerConfig{{URL: url}}
+	}
+	return matched
+}
+
+func loadConfig(file string) (*cliConfig, error) {
+	buf, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file, configFile=%s, err=%v", file, err)
+	}
+	var c cliConfig
+	if err := yaml.Unmarshal(buf, &c); err != nil {
+		return nil, fmt.Errorf("failed to parse config file, configFile=%s, err=%v", file, err)
+	}
+	return &c, nil
+}
@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/masa23/goloba/internal/apiclient"
+)
+
+func TestFilterAPIServersPreservesCredentials(t *testing.T) {
+	servers := []apiServerConfig{
+		{URL: "https://lb1:8880", Auth: apiclient.AuthConfig{BearerTokenFile: "/etc/goloba/lb1.token"}},
+		{URL: "https://lb2:8880", Auth: apiclient.AuthConfig{BearerTokenFile: "/etc/goloba/lb2.token"}},
+	}
+
+	got := filterAPIServers(servers, "https://lb1:8880")
+	if len(got) != 1 {
+		t.Fatalf("got %d servers, want 1", len(got))
+	}
+	if got[0].Auth.BearerTokenFile != "/etc/goloba/lb1.token" {
+		t.Errorf("Auth = %+v, want the lb1 entry's credentials preserved", got[0].Auth)
+	}
+}
+
+func TestFilterAPIServersFallsBackToBareEntry(t *testing.T) {
+	servers := []apiServerConfig{{URL: "https://lb1:8880"}}
+
+	got := filterAPIServers(servers, "https://adhoc:8880")
+	if len(got) != 1 || got[0].URL != "https://adhoc:8880" {
+		t.Fatalf("got %+v, want a single bare entry for the unconfigured URL", got)
+	}
+}
@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+
+	"github.com/masa23/goloba/internal/apiclient"
+)
+
+var (
+	unlockServiceAddr string
+	unlockDestAddr    string
+)
+
+var unlockCmd = &cobra.Command{
+	Use:   "unlock",
+	Short: "Clear a prior attach/detach lock for a destination",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runMutation("unlock", func(c *apiclient.Client) func(context.Context) (interface{}, error) {
+			return func(ctx context.Context) (interface{}, error) {
+				return c.Unlock(ctx, unlockServiceAddr, unlockDestAddr)
+			}
+		})
+	},
+}
+
+func init() {
+	unlockCmd.Flags().StringVarP(&unlockServiceAddr, "service", "s", "", "service address in <IPAddress>:<port> form")
+	unlockCmd.Flags().StringVarP(&unlockDestAddr, "dest", "d", "", "destination address in <IPAddress>:<port> form")
+	rootCmd.AddCommand(unlockCmd)
+}
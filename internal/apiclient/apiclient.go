@@ -0,0 +1,226 @@
+// Package apiclient talks to a single goloba API server, decoding its JSON
+// responses into the types exposed by github.com/masa23/goloba/api.
+package apiclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/masa23/goloba/api"
+)
+
+// Client is an HTTP client bound to a single goloba API server, including
+// its mTLS and bearer-token/basic-auth settings.
+type Client struct {
+	conf    Config
+	timeout time.Duration
+
+	mu         sync.RWMutex
+	httpClient *http.Client
+
+	credMu   sync.Mutex
+	credMods credModTimes
+}
+
+// New returns a Client that talks to the API server described by conf,
+// using timeout as the per-request timeout. The client certificate and CA
+// are loaded immediately; call Refresh later to pick up rotated files without
+// rebuilding the Client. The bearer token file and the token/password env
+// variables are read on every request instead.
+func New(conf Config, timeout time.Duration) (*Client, error) {
+	c := &Client{conf: conf, timeout: timeout}
+	if err := c.Refresh(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Refresh reloads the client certificate, key and CA from disk and swaps the
+// rebuilt transport in atomically. Bearer-token and basic-auth credentials
+// need no refresh: they are read on every request. Call it on SIGHUP,
+// or rely on reloadIfCredentialsRotated to call it automatically whenever the
+// configured cert/key/CA files' mtimes change, so rotated credentials take
+// effect without restarting golobactl.
+func (c *Client) Refresh() error {
+	base, err := buildTransport(c.conf.TLS)
+	if err != nil {
+		return err
+	}
+	httpClient := &http.Client{
+		Timeout:   c.timeout,
+		Transport: &authTransport{base: base, auth: c.conf.Auth},
+	}
+
+	c.mu.Lock()
+	c.httpClient = httpClient
+	c.mu.Unlock()
+
+	c.credMu.Lock()
+	c.credMods = currentCredModTimes(c.conf.TLS)
+	c.credMu.Unlock()
+	return nil
+}
+
+func (c *Client) client() *http.Client {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.httpClient
+}
+
+// reloadIfCredentialsRotated refreshes the transport if the configured
+// cert/key/CA files' mtimes have changed since they were last loaded. It is
+// called before every request, so a long-running poll loop (e.g.
+// `info --watch`) picks up rotated credentials on its own, without needing a
+// SIGHUP. A stat failure (e.g. a transient miss mid-rotation) reads as a
+// changed mtime and triggers a Refresh; if that Refresh fails, its error is
+// dropped and the request goes ahead with the previous transport, so it never
+// fails the request itself.
+func (c *Client) reloadIfCredentialsRotated() {
+	current := currentCredModTimes(c.conf.TLS)
+
+	c.credMu.Lock()
+	changed := current != c.credMods
+	c.credMu.Unlock()
+
+	if changed {
+		c.Refresh()
+	}
+}
+
+// Info fetches the current service/destination state from the server.
+func (c *Client) Info(ctx context.Context) (*api.Info, error) {
+	var info api.Info
+	if err := c.get(ctx, fmt.Sprintf("%s/info", c.conf.URL), &info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// Attach attaches dest to svc on the server. If lock is true, the
+// destination stays attached regardless of future healthcheck results.
+func (c *Client) Attach(ctx context.Context, svc, dest string, lock bool) (*api.Result, error) {
+	return c.mutate(ctx, "attach", svc, dest, lock)
+}
+
+// Detach detaches dest from svc on the server. If lock is true, the
+// destination stays detached regardless of future healthcheck results.
+func (c *Client) Detach(ctx context.Context, svc, dest string, lock bool) (*api.Result, error) {
+	return c.mutate(ctx, "detach", svc, dest, lock)
+}
+
+// Unlock clears a prior attach/detach lock for dest on svc, letting
+// healthcheck results govern its state again.
+func (c *Client) Unlock(ctx context.Context, svc, dest string) (*api.Result, error) {
+	if c.conf.APIVersion == "v0" {
+		u := fmt.Sprintf("%s/unlock?service=%s&dest=%s",
+			c.conf.URL, url.QueryEscape(svc), url.QueryEscape(dest))
+		var result api.Result
+		if err := c.get(ctx, u, &result); err != nil {
+			return nil, err
+		}
+		return &result, nil
+	}
+	var result api.Result
+	u := fmt.Sprintf("%s/v1/services/%s/destinations/%s/unlock", c.conf.URL, url.PathEscape(svc), url.PathEscape(dest))
+	if err := c.post(ctx, u, api.AttachRequest{}, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// mutate performs an attach/detach, using the versioned REST+JSON endpoint
+// by default and falling back to the legacy GET-with-query-string endpoint
+// when the client is configured for APIVersion "v0".
+func (c *Client) mutate(ctx context.Context, op, svc, dest string, lock bool) (*api.Result, error) {
+	var result api.Result
+	if c.conf.APIVersion == "v0" {
+		u := fmt.Sprintf("%s/%s?service=%s&dest=%s&lock=%v",
+			c.conf.URL, op, url.QueryEscape(svc), url.QueryEscape(dest), lock)
+		if err := c.get(ctx, u, &result); err != nil {
+			return nil, err
+		}
+		return &result, nil
+	}
+
+	u := fmt.Sprintf("%s/v1/services/%s/destinations/%s/%s", c.conf.URL, url.PathEscape(svc), url.PathEscape(dest), op)
+	if err := c.post(ctx, u, api.AttachRequest{Lock: lock}, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Transaction submits a batch of attach/detach/unlock ops to be applied
+// under a single lock on the server. It requires the v1 API; it returns an
+// error if the client is configured for APIVersion "v0".
+func (c *Client) Transaction(ctx context.Context, req api.TransactionRequest) (*api.TransactionResponse, error) {
+	if c.conf.APIVersion == "v0" {
+		return nil, fmt.Errorf("transactions require the v1 API; configured api-version is v0")
+	}
+	var resp api.TransactionResponse
+	u := fmt.Sprintf("%s/v1/transactions", c.conf.URL)
+	if err := c.post(ctx, u, req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+func (c *Client) get(ctx context.Context, u string, v interface{}) error {
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return fmt.Errorf("failed to build request, url=%s, err=%v", u, err)
+	}
+	req = req.WithContext(ctx)
+	return c.do(req, u, v)
+}
+
+func (c *Client) post(ctx context.Context, u string, body, v interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request body, url=%s, err=%v", u, err)
+	}
+	req, err := http.NewRequest(http.MethodPost, u, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to build request, url=%s, err=%v", u, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req = req.WithContext(ctx)
+	return c.do(req, u, v)
+}
+
+func (c *Client) do(req *http.Request, u string, v interface{}) error {
+	c.reloadIfCredentialsRotated()
+
+	resp, err := c.client().Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request, url=%s, err=%v", u, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response, url=%s, err=%v", u, err)
+	}
+	// A non-2xx response from the goloba API still carries a structured
+	// Result/TransactionResponse body for business-logic failures (a
+	// rejected attach, a rolled-back atomic transaction, ...), so decode it
+	// into v the same as a 2xx response and let the caller inspect
+	// Result.Error/TransactionResponse.Results. Only treat the response as an
+	// unrecoverable transport error if the body doesn't decode either.
+	if resp.StatusCode/100 != 2 {
+		if jsonErr := json.Unmarshal(data, v); jsonErr == nil {
+			return nil
+		}
+		return fmt.Errorf("non-2xx response, url=%s, status=%s, body=%s", u, resp.Status, string(data))
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("failed to unmarshal JSON response, url=%s, err=%v", u, err)
+	}
+	return nil
+}
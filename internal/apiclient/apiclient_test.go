@@ -0,0 +1,74 @@
+package apiclient
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/masa23/goloba/api"
+)
+
+func TestClientAttachUsesV1PathByDefault(t *testing.T) {
+	var gotPath, gotMethod string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotMethod = r.Method
+		json.NewEncoder(w).Encode(api.Result{Message: "ok"})
+	}))
+	defer srv.Close()
+
+	c, err := New(Config{URL: srv.URL}, time.Second)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if _, err := c.Attach(context.Background(), "10.0.0.1:80", "10.0.0.2:80", true); err != nil {
+		t.Fatalf("Attach: %v", err)
+	}
+	if gotMethod != http.MethodPost {
+		t.Errorf("method = %s, want POST", gotMethod)
+	}
+	if want := "/v1/services/10.0.0.1:80/destinations/10.0.0.2:80/attach"; gotPath != want {
+		t.Errorf("path = %s, want %s", gotPath, want)
+	}
+}
+
+func TestClientAttachFallsBackToV0(t *testing.T) {
+	var gotPath, gotMethod, gotQuery string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotMethod = r.Method
+		gotQuery = r.URL.RawQuery
+		json.NewEncoder(w).Encode(api.Result{Message: "ok"})
+	}))
+	defer srv.Close()
+
+	c, err := New(Config{URL: srv.URL, APIVersion: "v0"}, time.Second)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if _, err := c.Attach(context.Background(), "10.0.0.1:80", "10.0.0.2:80", true); err != nil {
+		t.Fatalf("Attach: %v", err)
+	}
+	if gotMethod != http.MethodGet {
+		t.Errorf("method = %s, want GET", gotMethod)
+	}
+	if gotPath != "/attach" {
+		t.Errorf("path = %s, want /attach", gotPath)
+	}
+	if gotQuery == "" {
+		t.Error("expected service/dest/lock in the query string")
+	}
+}
+
+func TestClientTransactionRejectedOnV0(t *testing.T) {
+	c, err := New(Config{URL: "http://example.invalid", APIVersion: "v0"}, time.Second)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if _, err := c.Transaction(context.Background(), api.TransactionRequest{}); err == nil {
+		t.Error("expected Transaction to fail fast on a v0-configured client")
+	}
+}
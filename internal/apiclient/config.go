@@ -0,0 +1,35 @@
+package apiclient
+
+// TLSConfig configures mTLS for a single API server. It is embedded in the
+// server's entry in golobactl.yml as `tls:`.
+type TLSConfig struct {
+	CAFile             string `yaml:"ca_file"`
+	CertFile           string `yaml:"cert_file"`
+	KeyFile            string `yaml:"key_file"`
+	ServerName         string `yaml:"server_name"`
+	InsecureSkipVerify bool   `yaml:"insecure_skip_verify"`
+}
+
+// AuthConfig configures bearer-token or basic authentication for a single
+// API server. It is embedded in the server's entry in golobactl.yml as
+// `auth:`. At most one of BearerTokenFile, BearerTokenEnv or BasicUser
+// should be set.
+type AuthConfig struct {
+	BearerTokenFile  string `yaml:"bearer_token_file"`
+	BearerTokenEnv   string `yaml:"bearer_token_env"`
+	BasicUser        string `yaml:"basic_user"`
+	BasicPasswordEnv string `yaml:"basic_password_env"`
+}
+
+// Config is everything needed to build a Client for one API server.
+type Config struct {
+	URL  string
+	TLS  TLSConfig
+	Auth AuthConfig
+
+	// APIVersion selects the wire protocol to speak: "v1" (default) uses
+	// the REST+JSON surface under /v1/; "v0" uses the legacy
+	// GET-with-query-string endpoints for one release's worth of
+	// backwards compatibility. Transaction is only available on v1.
+	APIVersion string
+}
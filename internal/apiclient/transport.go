@@ -0,0 +1,115 @@
+package apiclient
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// authTransport injects credentials on every request, reading the bearer
+// token file or env variables each time. It is rebuilt by Client.Refresh
+// whenever the underlying cert/key/CA files are rotated, so a long-running
+// process (e.g. `info --watch`) can pick up new credentials without
+// restarting.
+type authTransport struct {
+	base http.RoundTripper
+	auth AuthConfig
+}
+
+func (t *authTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	if err := t.setAuth(req); err != nil {
+		return nil, err
+	}
+	return t.base.RoundTrip(req)
+}
+
+func (t *authTransport) setAuth(req *http.Request) error {
+	switch {
+	case t.auth.BearerTokenFile != "":
+		tok, err := readTrimmedFile(t.auth.BearerTokenFile)
+		if err != nil {
+			return fmt.Errorf("failed to read bearer token file, file=%s, err=%v", t.auth.BearerTokenFile, err)
+		}
+		req.Header.Set("Authorization", "Bearer "+tok)
+	case t.auth.BearerTokenEnv != "":
+		req.Header.Set("Authorization", "Bearer "+os.Getenv(t.auth.BearerTokenEnv))
+	case t.auth.BasicUser != "":
+		req.SetBasicAuth(t.auth.BasicUser, os.Getenv(t.auth.BasicPasswordEnv))
+	}
+	return nil
+}
+
+func readTrimmedFile(file string) (string, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// buildTransport constructs the base (TLS-configuring) RoundTripper for
+// conf. It is called from New and from Refresh, so client certificate and CA
+// rotation just means calling Refresh again; no restart is required.
+func buildTransport(conf TLSConfig) (http.RoundTripper, error) {
+	tlsConf := &tls.Config{
+		ServerName:         conf.ServerName,
+		InsecureSkipVerify: conf.InsecureSkipVerify,
+	}
+
+	if conf.CAFile != "" {
+		pem, err := ioutil.ReadFile(conf.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA file, file=%s, err=%v", conf.CAFile, err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("failed to parse CA file, file=%s", conf.CAFile)
+		}
+		tlsConf.RootCAs = pool
+	}
+
+	if conf.CertFile != "" || conf.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(conf.CertFile, conf.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate, certFile=%s, keyFile=%s, err=%v", conf.CertFile, conf.KeyFile, err)
+		}
+		tlsConf.Certificates = []tls.Certificate{cert}
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConf
+	return transport, nil
+}
+
+// credModTimes captures the mtimes of the files buildTransport reads, so
+// Client can tell when they've been rotated on disk without needing a SIGHUP.
+type credModTimes struct {
+	cert, key, ca time.Time
+}
+
+func currentCredModTimes(conf TLSConfig) credModTimes {
+	return credModTimes{
+		cert: fileModTime(conf.CertFile),
+		key:  fileModTime(conf.KeyFile),
+		ca:   fileModTime(conf.CAFile),
+	}
+}
+
+// fileModTime returns path's mtime, or the zero Time if path is empty or
+// can't be stat'd (e.g. not configured, or transiently missing mid-rotation).
+func fileModTime(path string) time.Time {
+	if path == "" {
+		return time.Time{}
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
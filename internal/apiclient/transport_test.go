@@ -0,0 +1,88 @@
+package apiclient
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestAuthTransportSetAuth(t *testing.T) {
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(tokenFile, []byte("file-token\n"), 0600); err != nil {
+		t.Fatalf("write token file: %v", err)
+	}
+	t.Setenv("GOLOBACTL_TEST_TOKEN", "env-token")
+	t.Setenv("GOLOBACTL_TEST_PASSWORD", "hunter2")
+
+	tests := []struct {
+		name    string
+		auth    AuthConfig
+		wantHdr string
+	}{
+		{
+			name:    "bearer token file, trimmed",
+			auth:    AuthConfig{BearerTokenFile: tokenFile},
+			wantHdr: "Bearer file-token",
+		},
+		{
+			name:    "bearer token env",
+			auth:    AuthConfig{BearerTokenEnv: "GOLOBACTL_TEST_TOKEN"},
+			wantHdr: "Bearer env-token",
+		},
+		{
+			name:    "basic auth",
+			auth:    AuthConfig{BasicUser: "alice", BasicPasswordEnv: "GOLOBACTL_TEST_PASSWORD"},
+			wantHdr: "Basic YWxpY2U6aHVudGVyMg==",
+		},
+		{
+			name:    "no auth configured",
+			auth:    AuthConfig{},
+			wantHdr: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transport := &authTransport{auth: tt.auth}
+			req, err := http.NewRequest(http.MethodGet, "http://example.invalid/info", nil)
+			if err != nil {
+				t.Fatalf("NewRequest: %v", err)
+			}
+			if err := transport.setAuth(req); err != nil {
+				t.Fatalf("setAuth: %v", err)
+			}
+			if got := req.Header.Get("Authorization"); got != tt.wantHdr {
+				t.Errorf("Authorization header = %q, want %q", got, tt.wantHdr)
+			}
+		})
+	}
+}
+
+func TestAuthTransportBearerTokenFileMissing(t *testing.T) {
+	transport := &authTransport{auth: AuthConfig{BearerTokenFile: filepath.Join(t.TempDir(), "does-not-exist")}}
+	req, err := http.NewRequest(http.MethodGet, "http://example.invalid/info", nil)
+	if err != nil {
+		t.Fatalf("NewRequest: %v", err)
+	}
+	if err := transport.setAuth(req); err == nil {
+		t.Error("expected an error for a missing bearer token file")
+	}
+}
+
+func TestBuildTransportPlain(t *testing.T) {
+	transport, err := buildTransport(TLSConfig{})
+	if err != nil {
+		t.Fatalf("buildTransport: %v", err)
+	}
+	if transport == nil {
+		t.Error("expected a non-nil transport even with no TLS config set")
+	}
+}
+
+func TestBuildTransportMissingCAFile(t *testing.T) {
+	_, err := buildTransport(TLSConfig{CAFile: filepath.Join(t.TempDir(), "does-not-exist")})
+	if err == nil {
+		t.Error("expected an error for a missing CA file")
+	}
+}
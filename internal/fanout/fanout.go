@@ -0,0 +1,47 @@
+// Package fanout invokes a set of independent calls concurrently and
+// aggregates their results, preserving order.
+package fanout
+
+import (
+	"context"
+	"sync"
+)
+
+// Target is a single call to fan out, labeled for display/logging.
+type Target struct {
+	Label string
+	Call  func(ctx context.Context) (interface{}, error)
+}
+
+// Result is the outcome of calling a single Target.
+type Result struct {
+	Label string
+	Value interface{}
+	Err   error
+}
+
+// Run invokes every target's Call concurrently and returns one Result per
+// target, in the same order as targets. If a Call returns a nil error but
+// ctx has been canceled or its deadline exceeded by then, that target's
+// Result.Err is set to ctx.Err(); an error the Call itself returns is kept
+// as is.
+func Run(ctx context.Context, targets []Target) []Result {
+	results := make([]Result, len(targets))
+	var wg sync.WaitGroup
+	for i, t := range targets {
+		wg.Add(1)
+		i, t := i, t
+		go func() {
+			defer wg.Done()
+			v, err := t.Call(ctx)
+			if err == nil {
+				if ctxErr := ctx.Err(); ctxErr != nil {
+					err = ctxErr
+				}
+			}
+			results[i] = Result{Label: t.Label, Value: v, Err: err}
+		}()
+	}
+	wg.Wait()
+	return results
+}
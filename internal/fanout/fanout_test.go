@@ -0,0 +1,58 @@
+package fanout
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRunPreservesOrderRegardlessOfCompletionOrder(t *testing.T) {
+	targets := []Target{
+		{Label: "slow", Call: func(ctx context.Context) (interface{}, error) {
+			time.Sleep(20 * time.Millisecond)
+			return "slow-value", nil
+		}},
+		{Label: "fast", Call: func(ctx context.Context) (interface{}, error) {
+			return "fast-value", nil
+		}},
+	}
+
+	results := Run(context.Background(), targets)
+	if len(results) != 2 {
+		t.Fatalf("got %d results, want 2", len(results))
+	}
+	if results[0].Label != "slow" || results[0].Value != "slow-value" {
+		t.Errorf("results[0] = %+v, want the slow target despite finishing last", results[0])
+	}
+	if results[1].Label != "fast" || results[1].Value != "fast-value" {
+		t.Errorf("results[1] = %+v, want the fast target", results[1])
+	}
+}
+
+func TestRunSetsErrFromTarget(t *testing.T) {
+	wantErr := errors.New("boom")
+	targets := []Target{
+		{Label: "a", Call: func(ctx context.Context) (interface{}, error) { return nil, wantErr }},
+	}
+
+	results := Run(context.Background(), targets)
+	if results[0].Err != wantErr {
+		t.Errorf("Err = %v, want %v", results[0].Err, wantErr)
+	}
+}
+
+func TestRunSetsErrFromCanceledContextEvenOnNilTargetError(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	targets := []Target{
+		{Label: "a", Call: func(ctx context.Context) (interface{}, error) {
+			cancel()
+			return "value", nil
+		}},
+	}
+
+	results := Run(ctx, targets)
+	if results[0].Err != context.Canceled {
+		t.Errorf("Err = %v, want context.Canceled even though the target itself returned a nil error", results[0].Err)
+	}
+}